@@ -0,0 +1,247 @@
+// Package internal is a small hand-rolled client for the subset of
+// Netlify's DNS API that the netlify provider needs. It exists so the
+// provider doesn't have to pull in the entire netlify/open-api SDK to be
+// unit-tested, and so it can retry rate-limited and transiently-failing
+// requests without every call site reimplementing backoff.
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultBaseURL    = "https://api.netlify.com/api/v1"
+	defaultMaxRetries = 4
+)
+
+// Zone is the subset of a Netlify DNS zone this client cares about.
+type Zone struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Record is the subset of a Netlify DNS record this client cares about.
+type Record struct {
+	ID       string `json:"id"`
+	Hostname string `json:"hostname"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	TTL      int64  `json:"ttl"`
+	Priority int64  `json:"priority"`
+	Weight   int64  `json:"weight"`
+	Port     int64  `json:"port"`
+	Flag     int64  `json:"flag"`
+	Tag      string `json:"tag"`
+	Managed  bool   `json:"managed"`
+}
+
+// RecordCreate is the payload sent to create a DNS record.
+type RecordCreate struct {
+	Hostname string `json:"hostname"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	TTL      int64  `json:"ttl,omitempty"`
+	Priority int64  `json:"priority,omitempty"`
+	Weight   int64  `json:"weight,omitempty"`
+	Port     int64  `json:"port,omitempty"`
+	Flag     int64  `json:"flag,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+}
+
+// APIError is returned for any non-2xx response from the Netlify API. It
+// distinguishes the status codes the provider needs to branch on.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("netlify: %s (status %d): %s", http.StatusText(e.StatusCode), e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether the error is a 404 from the Netlify API.
+func IsNotFound(err error) bool { return hasStatus(err, http.StatusNotFound) }
+
+// IsConflict reports whether the error is a 409 from the Netlify API.
+func IsConflict(err error) bool { return hasStatus(err, http.StatusConflict) }
+
+// IsUnprocessable reports whether the error is a 422 from the Netlify API.
+func IsUnprocessable(err error) bool { return hasStatus(err, http.StatusUnprocessableEntity) }
+
+// IsRateLimited reports whether the error is a 429 from the Netlify API.
+func IsRateLimited(err error) bool { return hasStatus(err, http.StatusTooManyRequests) }
+
+func hasStatus(err error, status int) bool {
+	apiErr, ok := err.(*APIError)
+	return ok && apiErr.StatusCode == status
+}
+
+// Client is a minimal Netlify DNS API client with built-in retry/backoff.
+type Client struct {
+	HTTPClient *http.Client
+	BaseURL    string
+	Token      string
+
+	// MaxRetries bounds how many times a request is retried after a 429 or
+	// a 5xx on an idempotent (GET) request. It does not apply to non-GET
+	// requests on 5xx, since those aren't safe to blindly retry. Transport
+	// errors (no response received) are retried up to MaxRetries for every
+	// method.
+	MaxRetries int
+}
+
+// NewClient builds a Netlify API client. maxRetries < 0 means "unset" and
+// uses the default; maxRetries == 0 is honored as "no retries."
+func NewClient(token string, maxRetries int) *Client {
+	if maxRetries < 0 {
+		maxRetries = defaultMaxRetries
+	}
+	return &Client{
+		HTTPClient: &http.Client{Timeout: 30 * time.Second},
+		BaseURL:    defaultBaseURL,
+		Token:      token,
+		MaxRetries: maxRetries,
+	}
+}
+
+// ListZones returns one page of the account's DNS zones.
+func (c *Client) ListZones(page, perPage int) ([]Zone, error) {
+	q := url.Values{}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("per_page", strconv.Itoa(perPage))
+
+	var zones []Zone
+	if err := c.do(http.MethodGet, "/dns_zones?"+q.Encode(), nil, &zones); err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
+
+// CreateZone creates a new DNS zone for domain.
+func (c *Client) CreateZone(domain string) (Zone, error) {
+	var zone Zone
+	body := struct {
+		Name string `json:"name"`
+	}{Name: domain}
+	if err := c.do(http.MethodPost, "/dns_zones", body, &zone); err != nil {
+		return Zone{}, err
+	}
+	return zone, nil
+}
+
+// ListRecords returns every DNS record in the given zone.
+func (c *Client) ListRecords(zoneID string) ([]Record, error) {
+	var records []Record
+	if err := c.do(http.MethodGet, "/dns_zones/"+zoneID+"/dns_records", nil, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// CreateRecord creates a DNS record in the given zone.
+func (c *Client) CreateRecord(zoneID string, rec RecordCreate) (Record, error) {
+	var created Record
+	if err := c.do(http.MethodPost, "/dns_zones/"+zoneID+"/dns_records", rec, &created); err != nil {
+		return Record{}, err
+	}
+	return created, nil
+}
+
+// DeleteRecord deletes a DNS record by ID.
+func (c *Client) DeleteRecord(recordID string) error {
+	return c.do(http.MethodDelete, "/dns_records/"+recordID, nil, nil)
+}
+
+// do sends one request, retrying per the rules described on MaxRetries, and
+// decodes a JSON response body into out (if out is non-nil).
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var bodyBytes []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyBytes = b
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		req, err := http.NewRequest(method, c.BaseURL+path, bytes.NewReader(bodyBytes))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = err
+			if attempt < c.MaxRetries {
+				time.Sleep(backoff(attempt))
+				continue
+			}
+			return err
+		}
+
+		respBody, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr != nil {
+			return readErr
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			if out != nil && len(respBody) > 0 {
+				return json.Unmarshal(respBody, out)
+			}
+			return nil
+		}
+
+		apiErr := &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+		lastErr = apiErr
+
+		rateLimited := resp.StatusCode == http.StatusTooManyRequests
+		retryableServerError := resp.StatusCode >= 500 && method == http.MethodGet
+		if (rateLimited || retryableServerError) && attempt < c.MaxRetries {
+			wait := backoff(attempt)
+			if rateLimited {
+				if ra, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+					wait = ra
+				}
+			}
+			time.Sleep(wait)
+			continue
+		}
+
+		return apiErr
+	}
+
+	return lastErr
+}
+
+// backoff returns an exponential delay for the given retry attempt (100ms, 200ms, 400ms, ...).
+func backoff(attempt int) time.Duration {
+	return time.Duration(math.Pow(2, float64(attempt))) * 100 * time.Millisecond
+}
+
+// retryAfter parses a Retry-After header given in seconds. Netlify's API
+// does not use the HTTP-date form, so that's all this supports.
+func retryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
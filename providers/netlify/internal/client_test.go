@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	return newTestClientWithRetries(t, 2, handler)
+}
+
+func newTestClientWithRetries(t *testing.T, maxRetries int, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c := NewClient("test-token", maxRetries)
+	c.BaseURL = srv.URL
+	return c
+}
+
+func TestListZonesSuccess(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("got Authorization header %q", got)
+		}
+		w.Write([]byte(`[{"id":"z1","name":"example.com"}]`))
+	})
+
+	zones, err := c.ListZones(1, 100)
+	if err != nil {
+		t.Fatalf("ListZones: %v", err)
+	}
+	if len(zones) != 1 || zones[0].ID != "z1" {
+		t.Errorf("got %+v", zones)
+	}
+}
+
+func TestDoRetriesOnRateLimit(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`[{"id":"z1","name":"example.com"}]`))
+	})
+
+	zones, err := c.ListZones(1, 100)
+	if err != nil {
+		t.Fatalf("ListZones: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls (2 rate-limited + 1 success), got %d", calls)
+	}
+	if len(zones) != 1 {
+		t.Errorf("got %+v", zones)
+	}
+}
+
+func TestDoRetriesIdempotentGetOn5xx(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+
+	if _, err := c.ListRecords("z1"); err != nil {
+		t.Fatalf("ListRecords: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestDoDoesNotRetryCreateOn5xx(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	_, err := c.CreateRecord("z1", RecordCreate{Hostname: "@", Type: "A", Value: "1.2.3.4"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Errorf("expected CreateRecord to not be retried on 5xx, got %d calls", calls)
+	}
+}
+
+func TestDoGivesUpAfterMaxRetries(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, err := c.ListZones(1, 100)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !IsRateLimited(err) {
+		t.Errorf("expected a rate-limit error, got %v", err)
+	}
+	if calls != c.MaxRetries+1 {
+		t.Errorf("expected %d calls (initial + MaxRetries retries), got %d", c.MaxRetries+1, calls)
+	}
+}
+
+func TestMaxRetriesZeroMeansNoRetries(t *testing.T) {
+	calls := 0
+	c := newTestClientWithRetries(t, 0, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	if c.MaxRetries != 0 {
+		t.Fatalf("expected NewClient to honor an explicit max_retries of 0, got %d", c.MaxRetries)
+	}
+
+	_, err := c.ListZones(1, 100)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if calls != 1 {
+		t.Errorf("expected exactly 1 call with MaxRetries=0 (no retries), got %d", calls)
+	}
+}
+
+func TestAPIErrorClassification(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := c.ListZones(1, 100)
+	if !IsNotFound(err) {
+		t.Errorf("expected a not-found error, got %v", err)
+	}
+	if IsConflict(err) || IsRateLimited(err) {
+		t.Errorf("expected only IsNotFound to match, got %v", err)
+	}
+}
@@ -3,28 +3,44 @@ package netlify
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/StackExchange/dnscontrol/v3/models"
 	"github.com/StackExchange/dnscontrol/v3/pkg/diff"
 	"github.com/StackExchange/dnscontrol/v3/pkg/txtutil"
 	"github.com/StackExchange/dnscontrol/v3/providers"
-	"github.com/go-openapi/strfmt"
+	"github.com/StackExchange/dnscontrol/v3/providers/netlify/internal"
 	"github.com/miekg/dns/dnsutil"
-
-	httptransport "github.com/go-openapi/runtime/client"
-
-	netlifyModels "github.com/netlify/open-api/v2/go/models"
-	netlifyPlumbing "github.com/netlify/open-api/v2/go/plumbing"
-	netlifyOperations "github.com/netlify/open-api/v2/go/plumbing/operations"
-	netlify "github.com/netlify/open-api/v2/go/porcelain"
 )
 
 type netlifyProvider struct {
 	AccountToken string
+
+	client netlifyClient
+
+	// zonesByName caches the account's zone list so repeated lookups
+	// across domains in one run don't each re-list every zone.
+	zonesByName map[string]*internal.Zone
 }
 
+// netlifyClient is the subset of internal.Client this provider depends on.
+// It exists so tests can exercise GetDomainCorrections and friends against
+// a mock instead of real network calls.
+type netlifyClient interface {
+	ListZones(page, perPage int) ([]internal.Zone, error)
+	CreateZone(domain string) (internal.Zone, error)
+	ListRecords(zoneID string) ([]internal.Record, error)
+	CreateRecord(zoneID string, rec internal.RecordCreate) (internal.Record, error)
+	DeleteRecord(recordID string) error
+}
+
+// zonesPerPage is the page size used when paginating through the account's
+// zones; Netlify's API paginates the zone list rather than returning
+// everything in one page.
+const zonesPerPage = 100
+
 var features = providers.DocumentationNotes{
-	providers.CanUseAlias:            providers.Cannot(),
+	providers.CanUseAlias:            providers.Can(),
 	providers.CanUseCAA:              providers.Can(),
 	providers.CanUseNAPTR:            providers.Cannot(),
 	providers.CanUseDS:               providers.Cannot(),
@@ -33,7 +49,7 @@ var features = providers.DocumentationNotes{
 	providers.CanUseSRV:              providers.Can(),
 	providers.CanAutoDNSSEC:          providers.Cannot(),
 	providers.CanUseTLSA:             providers.Cannot(),
-	providers.DocCreateDomains:       providers.Cannot(),
+	providers.DocCreateDomains:       providers.Can(),
 	providers.DocDualHost:            providers.Cannot(),
 	providers.DocOfficiallySupported: providers.Can(),
 	providers.CanGetZones:            providers.Can(),
@@ -56,18 +72,6 @@ func init() {
 	providers.RegisterCustomRecordType("NETLIFY", "NETLIFY", "")
 }
 
-func (c *netlifyProvider) getClient() *netlify.Netlify {
-	transport := httptransport.New(
-		netlifyPlumbing.DefaultHost,
-		netlifyPlumbing.DefaultBasePath,
-		netlifyPlumbing.DefaultSchemes,
-	)
-
-	client := netlify.New(transport, strfmt.Default)
-
-	return client
-}
-
 // Creates the Netlify provider
 func newNetlify(m map[string]string, metadata json.RawMessage) (providers.DNSServiceProvider, error) {
 	api := &netlifyProvider{}
@@ -77,6 +81,19 @@ func newNetlify(m map[string]string, metadata json.RawMessage) (providers.DNSSer
 		return nil, fmt.Errorf("no Netlify Personal Access Token provided")
 	}
 
+	// -1 tells internal.NewClient "unset, use the default" so an explicit
+	// max_retries=0 (disable retries) isn't indistinguishable from unset.
+	maxRetries := -1
+	if s := m["max_retries"]; s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Netlify max_retries %q: %w", s, err)
+		}
+		maxRetries = n
+	}
+
+	api.client = internal.NewClient(api.AccountToken, maxRetries)
+
 	return api, nil
 }
 
@@ -85,6 +102,29 @@ func (api *netlifyProvider) GetNameservers(domain string) ([]*models.Nameserver,
 	return models.ToNameservers(defaultNameServerNames)
 }
 
+// EnsureDomainExists creates the zone on Netlify if it isn't already present in the account.
+func (api *netlifyProvider) EnsureDomainExists(domain string) error {
+	zone, err := api.getZoneByName(domain)
+	if err != nil {
+		return err
+	}
+	if zone != nil {
+		return nil
+	}
+
+	created, err := api.client.CreateZone(domain)
+	if err != nil {
+		return err
+	}
+
+	if api.zonesByName == nil {
+		api.zonesByName = map[string]*internal.Zone{}
+	}
+	api.zonesByName[domain] = &created
+
+	return nil
+}
+
 // GetZoneRecords gets the records of a zone and returns them in RecordConfig format.
 func (api *netlifyProvider) GetZoneRecords(domain string) (models.Records, error) {
 	// Loop over the Netlify records and convert them to native
@@ -108,48 +148,72 @@ func (api *netlifyProvider) GetZoneRecords(domain string) (models.Records, error
 	return existingRecords, nil
 }
 
-// Gets records for a passed domain by looping through all the zones we have access to from our token
-func getRecords(api *netlifyProvider, name string) ([]*netlifyModels.DNSRecord, error) {
-	c := api.getClient()
-	authInfo := httptransport.BearerToken(api.AccountToken)
-
-	// Get the list of domains we have access to
-	params := netlifyOperations.NewGetDNSZonesParams()
-	zoneList, err := c.Operations.GetDNSZones(params, authInfo)
+// Gets records for a passed domain, using the cached zone list to find the zone ID.
+func getRecords(api *netlifyProvider, name string) ([]*internal.Record, error) {
+	zone, err := api.getZoneByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil {
+		return []*internal.Record{}, nil
+	}
 
+	rs, err := api.client.ListRecords(zone.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create an ep
-	records := []*netlifyModels.DNSRecord{}
+	records := make([]*internal.Record, len(rs))
+	for j := range rs {
+		records[j] = &rs[j]
+	}
 
-	// Loop over the list of zones
-	for i := range zoneList.Payload {
-		zone := zoneList.Payload[i]
+	return records, nil
+}
 
-		// Look for a domain which matches what we're looking for
-		if zone.Name == name {
-			rs, err := c.Operations.GetDNSRecords(netlifyOperations.NewGetDNSRecordsParams().WithZoneID(zone.ID), authInfo)
-			if err != nil {
-				return nil, err
-			}
+// getZoneByName looks up a zone by name, fetching and caching the account's
+// full zone list on first use so later lookups (across domains, and across
+// GetZoneRecords/GetDomainCorrections) don't re-list every zone. Returns a
+// nil zone (no error) if the account has no zone with that name.
+func (api *netlifyProvider) getZoneByName(name string) (*internal.Zone, error) {
+	if api.zonesByName == nil {
+		if err := api.loadZones(); err != nil {
+			return nil, err
+		}
+	}
+
+	return api.zonesByName[name], nil
+}
 
-			for j := range rs.Payload {
-				r := rs.Payload[j]
+// loadZones fetches every zone in the account, walking the zone list's
+// page/per_page pagination until an empty page is returned, and populates
+// zonesByName.
+func (api *netlifyProvider) loadZones() error {
+	zonesByName := map[string]*internal.Zone{}
 
-				records = append(records, r)
-			}
+	for page := 1; ; page++ {
+		zones, err := api.client.ListZones(page, zonesPerPage)
+		if err != nil {
+			return err
+		}
+
+		if len(zones) == 0 {
+			break
+		}
+
+		for i := range zones {
+			zone := zones[i]
+			zonesByName[zone.Name] = &zone
 		}
 	}
 
-	return records, nil
+	api.zonesByName = zonesByName
+	return nil
 }
 
 // GetDomainCorrections returns corrections that update a domain.
 func (api *netlifyProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*models.Correction, error) {
-	c := api.getClient()
-	authInfo := httptransport.BearerToken(api.AccountToken)
+	c := api.client
 
 	dc.Punycode()
 
@@ -158,6 +222,14 @@ func (api *netlifyProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 		return nil, err
 	}
 
+	zone, err := api.getZoneByName(dc.Name)
+	if err != nil {
+		return nil, err
+	}
+	if zone == nil {
+		return nil, fmt.Errorf("%q is not a zone in this Netlify account", dc.Name)
+	}
+
 	// Normalize
 	models.PostProcessRecords(existingRecords)
 	txtutil.SplitSingleLongTxt(dc.Records) // Autosplit long TXT records
@@ -170,63 +242,48 @@ func (api *netlifyProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 
 	var corrections = []*models.Correction{}
 
-	// DeleteDNSRecord: Deletes first so changing type works etc.
+	// DeleteRecord: Deletes first so changing type works etc.
 	for _, m := range delete {
-		id := m.Existing.Original.(*netlifyModels.DNSRecord).ID
+		id := m.Existing.Original.(*internal.Record).ID
 		corr := &models.Correction{
 			Msg: fmt.Sprintf("%s, Netlify DNSZoneID: %s", m.String(), id),
 			F: func() error {
-				params := netlifyOperations.NewDeleteDNSRecordParams().WithDNSRecordID(id)
-				res, err := c.Operations.DeleteDNSRecord(params, authInfo)
-				if err != nil {
-					return err
-				}
-				return res
+				return c.DeleteRecord(id)
 			},
 		}
 		corrections = append(corrections, corr)
 	}
 
-	// CreateDNSRecord
+	// CreateRecord
 	for _, m := range create {
 		req := toReq(dc, m.Desired)
 		corr := &models.Correction{
 			Msg: m.String(),
 			F: func() error {
-				res, err := c.Operations.CreateDNSRecord(netlifyOperations.NewCreateDNSRecordParams().WithDNSRecord(req), authInfo)
-				if err != nil {
-					return err
-				}
-				return res
+				_, err := c.CreateRecord(zone.ID, req)
+				return err
 			},
 		}
 		corrections = append(corrections, corr)
 	}
 
-	// There is no update so DeleteDNSRecord then CreateDNSRecord
+	// There is no update so DeleteRecord then CreateRecord
 	for _, m := range modify {
-		id := m.Existing.Original.(*netlifyModels.DNSRecord).ID
+		id := m.Existing.Original.(*internal.Record).ID
 		req := toReq(dc, m.Desired)
 
 		corrections = append(corrections,
 			&models.Correction{
 				Msg: fmt.Sprintf("%s, Netlify DNSZoneID: %s", m.String(), id),
 				F: func() error {
-					res, err := c.Operations.DeleteDNSRecord(netlifyOperations.NewDeleteDNSRecordParams().WithDNSRecordID(id), authInfo)
-					if err != nil {
-						return err
-					}
-					return res
+					return c.DeleteRecord(id)
 				},
 			},
 			&models.Correction{
 				Msg: fmt.Sprintf("%s, Netlify DNSZoneID: %s", m.String(), id),
 				F: func() error {
-					res, err := c.Operations.CreateDNSRecord(netlifyOperations.NewCreateDNSRecordParams().WithDNSRecord(req), authInfo)
-					if err != nil {
-						return err
-					}
-					return res
+					_, err := c.CreateRecord(zone.ID, req)
+					return err
 				},
 			},
 		)
@@ -236,7 +293,7 @@ func (api *netlifyProvider) GetDomainCorrections(dc *models.DomainConfig) ([]*mo
 }
 
 // Converts a DNS record to the expected format for dnsconfig
-func recordToNative(domain string, r *netlifyModels.DNSRecord) (*models.RecordConfig, error) {
+func recordToNative(domain string, r *internal.Record) (*models.RecordConfig, error) {
 	if !r.Managed {
 		return nil, fmt.Errorf(r.Hostname + " is not managed by Netlify")
 	}
@@ -246,7 +303,7 @@ func recordToNative(domain string, r *netlifyModels.DNSRecord) (*models.RecordCo
 
 	target := r.Value
 	// Make target FQDN (#rtype_variations)
-	if r.Type == "CNAME" || r.Type == "MX" || r.Type == "NS" || r.Type == "SRV" {
+	if r.Type == "CNAME" || r.Type == "MX" || r.Type == "NS" || r.Type == "SRV" || r.Type == "NETLIFY" {
 		// If target is the domainname, e.g. cname foo.example.com -> example.com,
 		// DO returns "@" on read even if fqdn was written.
 		if target == "@" {
@@ -257,8 +314,15 @@ func recordToNative(domain string, r *netlifyModels.DNSRecord) (*models.RecordCo
 		target = target + "."
 	}
 
+	// Netlify has no native ALIAS record type; apex aliasing is done with its
+	// own "NETLIFY" record type, which dnscontrol represents as ALIAS.
+	dcType := r.Type
+	if dcType == "NETLIFY" {
+		dcType = "ALIAS"
+	}
+
 	t := &models.RecordConfig{
-		Type:         r.Type,
+		Type:         dcType,
 		TTL:          uint32(r.TTL),
 		MxPreference: uint16(r.Priority),
 		SrvPriority:  uint16(r.Priority),
@@ -279,9 +343,16 @@ func recordToNative(domain string, r *netlifyModels.DNSRecord) (*models.RecordCo
 	return t, nil
 }
 
-func toReq(dc *models.DomainConfig, rc *models.RecordConfig) *netlifyModels.DNSRecordCreate {
-	return &netlifyModels.DNSRecordCreate{
-		Type:     rc.Type,
+func toReq(dc *models.DomainConfig, rc *models.RecordConfig) internal.RecordCreate {
+	// Netlify has no native ALIAS record type; it uses its own "NETLIFY" type
+	// for apex aliasing instead.
+	nativeType := rc.Type
+	if nativeType == "ALIAS" {
+		nativeType = "NETLIFY"
+	}
+
+	return internal.RecordCreate{
+		Type:     nativeType,
 		Hostname: dnsutil.AddOrigin(rc.Name, dc.Name),
 		Value:    rc.GetTargetField(),
 		Priority: int64(rc.SrvPriority),
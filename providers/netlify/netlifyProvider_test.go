@@ -0,0 +1,199 @@
+package netlify
+
+import (
+	"testing"
+
+	"github.com/StackExchange/dnscontrol/v3/models"
+	"github.com/StackExchange/dnscontrol/v3/providers/netlify/internal"
+)
+
+// mockClient is a minimal netlifyClient stand-in so these tests don't depend
+// on a live Netlify account or the real HTTP transport.
+type mockClient struct {
+	zonesByPage map[int]([]internal.Zone)
+	created     []string
+
+	records       []internal.Record
+	createdRecord []internal.RecordCreate
+	deletedIDs    []string
+}
+
+func (m *mockClient) ListZones(page, perPage int) ([]internal.Zone, error) {
+	return m.zonesByPage[page], nil
+}
+
+func (m *mockClient) CreateZone(domain string) (internal.Zone, error) {
+	m.created = append(m.created, domain)
+	return internal.Zone{Name: domain, ID: "new-zone-id"}, nil
+}
+
+func (m *mockClient) ListRecords(zoneID string) ([]internal.Record, error) {
+	return m.records, nil
+}
+
+func (m *mockClient) CreateRecord(zoneID string, rec internal.RecordCreate) (internal.Record, error) {
+	m.createdRecord = append(m.createdRecord, rec)
+	return internal.Record{ID: "new-record-id", Hostname: rec.Hostname, Type: rec.Type, Value: rec.Value, Managed: true}, nil
+}
+
+func (m *mockClient) DeleteRecord(recordID string) error {
+	m.deletedIDs = append(m.deletedIDs, recordID)
+	return nil
+}
+
+func zone(name string) internal.Zone {
+	return internal.Zone{Name: name, ID: name + "-id"}
+}
+
+func TestLoadZonesWalksAllPages(t *testing.T) {
+	client := &mockClient{
+		zonesByPage: map[int][]internal.Zone{
+			1: {zone("page1-a.com"), zone("page1-b.com")},
+			2: {zone("page2-a.com")},
+			3: {zone("page3-a.com")},
+			4: {},
+		},
+	}
+	api := &netlifyProvider{client: client}
+
+	got, err := api.getZoneByName("page3-a.com")
+	if err != nil {
+		t.Fatalf("getZoneByName: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected to find a zone on page 3, got nil")
+	}
+	if got.ID != "page3-a.com-id" {
+		t.Errorf("got zone ID %q, want %q", got.ID, "page3-a.com-id")
+	}
+	if len(api.zonesByName) != 4 {
+		t.Errorf("expected all 4 zones across 3 pages to be cached, got %d", len(api.zonesByName))
+	}
+}
+
+func TestEnsureDomainExistsCreatesMissingZone(t *testing.T) {
+	client := &mockClient{
+		zonesByPage: map[int][]internal.Zone{
+			1: {zone("existing.com")},
+			2: {},
+		},
+	}
+	api := &netlifyProvider{client: client}
+
+	if err := api.EnsureDomainExists("existing.com"); err != nil {
+		t.Fatalf("EnsureDomainExists on existing zone: %v", err)
+	}
+	if len(client.created) != 0 {
+		t.Errorf("expected no CreateZone call for an existing zone, got %d", len(client.created))
+	}
+
+	if err := api.EnsureDomainExists("new.com"); err != nil {
+		t.Fatalf("EnsureDomainExists on missing zone: %v", err)
+	}
+	if len(client.created) != 1 || client.created[0] != "new.com" {
+		t.Errorf("expected CreateZone to be called once for new.com, got %+v", client.created)
+	}
+	if api.zonesByName["new.com"] == nil {
+		t.Error("expected the newly created zone to be cached")
+	}
+}
+
+// TestApexAliasSwitchDeletesThenCreates exercises switching an apex ALIAS
+// target: Netlify has no update endpoint, and an apex ALIAS ("NETLIFY" native
+// type) can't coexist with a CNAME, so the old record must be deleted before
+// the new one is created.
+func TestApexAliasSwitchDeletesThenCreates(t *testing.T) {
+	const domain = "example.com"
+
+	client := &mockClient{
+		zonesByPage: map[int][]internal.Zone{
+			1: {zone(domain)},
+			2: {},
+		},
+		records: []internal.Record{
+			{ID: "rec1", Managed: true, Hostname: domain, Type: "NETLIFY", Value: "old-target.netlify.app"},
+		},
+	}
+	api := &netlifyProvider{client: client}
+
+	desired := &models.RecordConfig{Type: "ALIAS"}
+	desired.SetLabelFromFQDN(domain, domain)
+	desired.SetTarget("new-target.netlify.app.")
+
+	dc := &models.DomainConfig{Name: domain, Records: models.Records{desired}}
+
+	corrections, err := api.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatalf("GetDomainCorrections: %v", err)
+	}
+
+	for _, c := range corrections {
+		if err := c.F(); err != nil {
+			t.Fatalf("correction %q failed: %v", c.Msg, err)
+		}
+	}
+
+	if len(client.deletedIDs) != 1 || client.deletedIDs[0] != "rec1" {
+		t.Errorf("expected the old apex record (rec1) to be deleted, got %v", client.deletedIDs)
+	}
+	if len(client.createdRecord) != 1 {
+		t.Fatalf("expected one CreateRecord call, got %d", len(client.createdRecord))
+	}
+	created := client.createdRecord[0]
+	if created.Type != "NETLIFY" {
+		t.Errorf("expected the ALIAS record to be created as native type NETLIFY, got %q", created.Type)
+	}
+	if created.Value != "new-target.netlify.app." {
+		t.Errorf("got created value %q, want %q", created.Value, "new-target.netlify.app.")
+	}
+}
+
+// TestNSDelegationAtSubdomainRoundTrips confirms an NS record at a
+// non-apex label (subzone delegation) round-trips through GetZoneRecords
+// and is recognized as already-in-sync by GetDomainCorrections, using the
+// same CNAME/MX/SRV-style FQDN handling already in recordToNative/toReq.
+func TestNSDelegationAtSubdomainRoundTrips(t *testing.T) {
+	const domain = "example.com"
+
+	client := &mockClient{
+		zonesByPage: map[int][]internal.Zone{
+			1: {zone(domain)},
+			2: {},
+		},
+		records: []internal.Record{
+			{ID: "rec1", Managed: true, Hostname: "sub", Type: "NS", Value: "ns1.example-dns.com"},
+		},
+	}
+	api := &netlifyProvider{client: client}
+
+	existing, err := api.GetZoneRecords(domain)
+	if err != nil {
+		t.Fatalf("GetZoneRecords: %v", err)
+	}
+	if len(existing) != 1 {
+		t.Fatalf("expected 1 existing record, got %d", len(existing))
+	}
+	if existing[0].Type != "NS" {
+		t.Errorf("got type %q, want NS", existing[0].Type)
+	}
+	if existing[0].Name != "sub" {
+		t.Errorf("got label %q, want %q", existing[0].Name, "sub")
+	}
+	if got := existing[0].GetTargetField(); got != "ns1.example-dns.com." {
+		t.Errorf("got target %q, want %q", got, "ns1.example-dns.com.")
+	}
+
+	desired := &models.RecordConfig{Type: "NS"}
+	desired.SetLabelFromFQDN("sub."+domain, domain)
+	desired.SetTarget("ns1.example-dns.com.")
+
+	dc := &models.DomainConfig{Name: domain, Records: models.Records{desired}}
+
+	corrections, err := api.GetDomainCorrections(dc)
+	if err != nil {
+		t.Fatalf("GetDomainCorrections: %v", err)
+	}
+	if len(corrections) != 0 {
+		t.Errorf("expected no corrections for an already-in-sync NS delegation, got %d: %v", len(corrections), corrections)
+	}
+}